@@ -0,0 +1,251 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// BlockerService wraps the client context to operate on the blockers
+// belonging to a single story.
+type BlockerService struct {
+	client *Client
+}
+
+func newBlockerService(client *Client) *BlockerService {
+	return &BlockerService{client}
+}
+
+func newBlockersRequestFunc(client *Client, projectId, storyId int) func() *http.Request {
+	return func() *http.Request {
+		u := fmt.Sprintf("projects/%v/stories/%v/blockers", projectId, storyId)
+		req, _ := client.NewRequest("GET", u, nil)
+		return req
+	}
+}
+
+// List returns all the blockers belonging to the given story.
+func (service *BlockerService) List(projectId, storyId int) ([]*Blocker, error) {
+	reqFunc := newBlockersRequestFunc(service.client, projectId, storyId)
+	cursor, err := newCursor(service.client, reqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockers []*Blocker
+	if err := cursor.all(&blockers); err != nil {
+		return nil, err
+	}
+	return blockers, nil
+}
+
+// ListContext is the same as List, but it carries ctx for cancellation and
+// deadlines.
+func (service *BlockerService) ListContext(ctx context.Context, projectId, storyId int) ([]*Blocker, error) {
+	reqFunc := newBlockersRequestFunc(service.client, projectId, storyId)
+	ctxReqFunc := func() *http.Request { return reqFunc().WithContext(ctx) }
+	cursor, err := newCursor(service.client, ctxReqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockers []*Blocker
+	if err := cursor.all(&blockers); err != nil {
+		return nil, err
+	}
+	return blockers, nil
+}
+
+// BlockerCursor is used to implement the iterator pattern over blockers.
+type BlockerCursor struct {
+	*cursor
+	buff []*Blocker
+}
+
+// Next returns the next blocker.
+//
+// In case there are no more blockers, io.EOF is returned as an error.
+func (c *BlockerCursor) Next() (b *Blocker, err error) {
+	if len(c.buff) == 0 {
+		_, err = c.next(&c.buff)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.buff) == 0 {
+		err = io.EOF
+	} else {
+		b, c.buff = c.buff[0], c.buff[1:]
+	}
+	return b, err
+}
+
+// Iterate returns a cursor that can be used to iterate over the blockers
+// belonging to the given story. More blockers are fetched on demand as
+// needed.
+func (service *BlockerService) Iterate(projectId, storyId int) (*BlockerCursor, error) {
+	reqFunc := newBlockersRequestFunc(service.client, projectId, storyId)
+	cursor, err := newCursor(service.client, reqFunc, PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockerCursor{cursor, make([]*Blocker, 0)}, nil
+}
+
+// IterateContext is the same as Iterate, but it carries ctx for cancellation
+// and deadlines.
+func (service *BlockerService) IterateContext(ctx context.Context, projectId, storyId int) (*BlockerCursor, error) {
+	reqFunc := newBlockersRequestFunc(service.client, projectId, storyId)
+	ctxReqFunc := func() *http.Request { return reqFunc().WithContext(ctx) }
+	cursor, err := newCursor(service.client, ctxReqFunc, PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockerCursor{cursor, make([]*Blocker, 0)}, nil
+}
+
+// Get returns a single blocker by ID.
+func (service *BlockerService) Get(projectId, storyId, blockerId int) (*Blocker, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, storyId, blockerId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var blocker Blocker
+	resp, err := service.client.Do(req, &blocker)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &blocker, resp, nil
+}
+
+// GetContext is the same as Get, but it carries ctx for cancellation and
+// deadlines.
+func (service *BlockerService) GetContext(ctx context.Context, projectId, storyId, blockerId int) (*Blocker, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, storyId, blockerId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var blocker Blocker
+	resp, err := service.client.Do(req, &blocker)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &blocker, resp, nil
+}
+
+// Create adds a new blocker to the given story.
+func (service *BlockerService) Create(projectId, storyId int, blocker *BlockerRequest) (*Blocker, *http.Response, error) {
+	if blocker.Description == "" {
+		return nil, nil, &ErrFieldNotSet{"description"}
+	}
+
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers", projectId, storyId)
+	req, err := service.client.NewRequest("POST", u, blocker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var newBlocker Blocker
+	resp, err := service.client.Do(req, &newBlocker)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newBlocker, resp, nil
+}
+
+// CreateContext is the same as Create, but it carries ctx for cancellation
+// and deadlines.
+func (service *BlockerService) CreateContext(ctx context.Context, projectId, storyId int, blocker *BlockerRequest) (*Blocker, *http.Response, error) {
+	if blocker.Description == "" {
+		return nil, nil, &ErrFieldNotSet{"description"}
+	}
+
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers", projectId, storyId)
+	req, err := service.client.NewRequest("POST", u, blocker)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var newBlocker Blocker
+	resp, err := service.client.Do(req, &newBlocker)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newBlocker, resp, nil
+}
+
+// Update updates an existing blocker.
+func (service *BlockerService) Update(projectId, storyId, blockerId int, blocker *BlockerRequest) (*Blocker, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, storyId, blockerId)
+	req, err := service.client.NewRequest("PUT", u, blocker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updatedBlocker Blocker
+	resp, err := service.client.Do(req, &updatedBlocker)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedBlocker, resp, nil
+}
+
+// UpdateContext is the same as Update, but it carries ctx for cancellation
+// and deadlines.
+func (service *BlockerService) UpdateContext(ctx context.Context, projectId, storyId, blockerId int, blocker *BlockerRequest) (*Blocker, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, storyId, blockerId)
+	req, err := service.client.NewRequest("PUT", u, blocker)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var updatedBlocker Blocker
+	resp, err := service.client.Do(req, &updatedBlocker)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedBlocker, resp, nil
+}
+
+// Delete removes a blocker from the given story.
+func (service *BlockerService) Delete(projectId, storyId, blockerId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, storyId, blockerId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.Do(req, nil)
+}
+
+// DeleteContext is the same as Delete, but it carries ctx for cancellation
+// and deadlines.
+func (service *BlockerService) DeleteContext(ctx context.Context, projectId, storyId, blockerId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, storyId, blockerId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	return service.client.Do(req, nil)
+}
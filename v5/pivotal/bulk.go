@@ -0,0 +1,273 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import "sync"
+
+// bulkChunkSize caps how many IDs are sent in a single stories/bulk
+// request, staying well under Pivotal Tracker's URL length and per-request
+// limits.
+const bulkChunkSize = 50
+
+// defaultBulkConcurrency bounds how many chunks/items are requested in
+// parallel by the bulk operations below.
+const defaultBulkConcurrency = 4
+
+// BulkOption configures a bulk operation's chunk size and concurrency.
+type BulkOption func(*bulkConfig)
+
+type bulkConfig struct {
+	chunkSize   int
+	concurrency int
+}
+
+// WithChunkSize overrides the number of IDs sent per request.
+func WithChunkSize(n int) BulkOption {
+	return func(cfg *bulkConfig) { cfg.chunkSize = n }
+}
+
+// WithConcurrency overrides how many requests a bulk operation keeps in
+// flight at once.
+func WithConcurrency(n int) BulkOption {
+	return func(cfg *bulkConfig) { cfg.concurrency = n }
+}
+
+func resolveBulkConfig(opts []BulkOption) *bulkConfig {
+	cfg := &bulkConfig{chunkSize: bulkChunkSize, concurrency: defaultBulkConcurrency}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.chunkSize <= 0 {
+		cfg.chunkSize = bulkChunkSize
+	}
+	if cfg.concurrency <= 0 {
+		cfg.concurrency = 1
+	}
+	return cfg
+}
+
+// chunkIDs splits ids into consecutive slices of at most size elements.
+func chunkIDs(ids []int, size int) [][]int {
+	var chunks [][]int
+	for size < len(ids) {
+		ids, chunks = ids[size:], append(chunks, ids[:size:size])
+	}
+	return append(chunks, ids)
+}
+
+// BulkResult reports the outcome of a chunked bulk operation on existing
+// stories: the stories that were fetched or updated successfully, plus any
+// per-story-ID errors.
+type BulkResult struct {
+	Stories []*Story
+	Errors  map[int]error
+}
+
+// BulkCreateResult reports the outcome of CreateBulk. Stories is index-
+// aligned with the []*StoryRequest passed to CreateBulk: Stories[i] holds
+// the created Story for stories[i], or nil if it failed, in which case
+// Errors[i] holds the reason.
+type BulkCreateResult struct {
+	Stories []*Story
+	Errors  map[int]error
+}
+
+// GetBulkChunked is like GetBulk, but it automatically splits storyIds into
+// safely-sized chunks (see WithChunkSize), fetches up to WithConcurrency
+// chunks in parallel, and reports a chunk's failure in BulkResult.Errors
+// against every ID it covers instead of aborting the whole operation.
+func (service *StoryService) GetBulkChunked(projectId int, storyIds []int, opts ...BulkOption) *BulkResult {
+	cfg := resolveBulkConfig(opts)
+	result := &BulkResult{Errors: make(map[int]error)}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, cfg.concurrency)
+	)
+
+	for _, chunk := range chunkIDs(storyIds, cfg.chunkSize) {
+		chunk := chunk
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			stories, _, err := service.GetBulk(projectId, chunk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				for _, id := range chunk {
+					result.Errors[id] = err
+				}
+				return
+			}
+			result.Stories = append(result.Stories, stories...)
+		}()
+	}
+
+	wg.Wait()
+	return result
+}
+
+// CreateBulk creates each of the given stories, running up to
+// WithConcurrency requests in parallel and reporting a story's failure in
+// BulkCreateResult.Errors, keyed by its index in stories, instead of
+// aborting on the first error. BulkCreateResult.Stories is index-aligned
+// with stories, so a caller can correlate a result back to the request
+// that produced it even when some requests fail.
+func (service *StoryService) CreateBulk(projectId int, stories []*StoryRequest, opts ...BulkOption) *BulkCreateResult {
+	cfg := resolveBulkConfig(opts)
+	result := &BulkCreateResult{
+		Stories: make([]*Story, len(stories)),
+		Errors:  make(map[int]error),
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, cfg.concurrency)
+	)
+
+	for i, story := range stories {
+		i, story := i, story
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			created, _, err := service.Create(projectId, story)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[i] = err
+				return
+			}
+			result.Stories[i] = created
+		}()
+	}
+
+	wg.Wait()
+	return result
+}
+
+// DeleteBulk deletes each of the given stories, running up to
+// WithConcurrency requests in parallel and reporting a story's failure in
+// a map keyed by its ID instead of aborting on the first one.
+func (service *StoryService) DeleteBulk(projectId int, storyIds []int, opts ...BulkOption) map[int]error {
+	cfg := resolveBulkConfig(opts)
+	errs := make(map[int]error)
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, cfg.concurrency)
+	)
+
+	for _, id := range storyIds {
+		id := id
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := service.Delete(projectId, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = err
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// AddLabelBulk adds label to each of the given stories, running up to
+// WithConcurrency requests in parallel and reporting a story's failure in
+// BulkResult.Errors instead of aborting on the first one.
+func (service *StoryService) AddLabelBulk(projectId int, storyIds []int, label string, opts ...BulkOption) *BulkResult {
+	return service.bulkUpdateLabel(projectId, storyIds, label, true, opts...)
+}
+
+// RemoveLabelBulk removes label from each of the given stories, running up
+// to WithConcurrency requests in parallel and reporting a story's failure
+// in BulkResult.Errors instead of aborting on the first one.
+func (service *StoryService) RemoveLabelBulk(projectId int, storyIds []int, label string, opts ...BulkOption) *BulkResult {
+	return service.bulkUpdateLabel(projectId, storyIds, label, false, opts...)
+}
+
+func (service *StoryService) bulkUpdateLabel(projectId int, storyIds []int, label string, add bool, opts ...BulkOption) *BulkResult {
+	cfg := resolveBulkConfig(opts)
+	result := &BulkResult{Errors: make(map[int]error)}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, cfg.concurrency)
+	)
+
+	for _, id := range storyIds {
+		id := id
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			updated, err := service.applyLabelChange(projectId, id, label, add)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[id] = err
+				return
+			}
+			result.Stories = append(result.Stories, updated)
+		}()
+	}
+
+	wg.Wait()
+	return result
+}
+
+// applyLabelChange fetches the story's current labels, adds or removes
+// label, and writes the result back with Update.
+func (service *StoryService) applyLabelChange(projectId, storyId int, label string, add bool) (*Story, error) {
+	story, _, err := service.Get(projectId, storyId)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(story.Labels)+1)
+	present := false
+	for _, l := range story.Labels {
+		if l.Name == label {
+			present = true
+			if !add {
+				continue
+			}
+		}
+		names = append(names, l.Name)
+	}
+	if add && !present {
+		names = append(names, label)
+	}
+
+	labels := make([]*Label, len(names))
+	for i, name := range names {
+		labels[i] = &Label{Name: name}
+	}
+
+	updated, _, err := service.Update(projectId, storyId, &StoryRequest{Labels: &labels})
+	return updated, err
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestChunkIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []int
+		size int
+		want [][]int
+	}{
+		{name: "empty", ids: nil, size: 2, want: [][]int{nil}},
+		{name: "exact multiple", ids: []int{1, 2, 3, 4}, size: 2, want: [][]int{{1, 2}, {3, 4}}},
+		{name: "remainder", ids: []int{1, 2, 3}, size: 2, want: [][]int{{1, 2}, {3}}},
+		{name: "size larger than input", ids: []int{1, 2}, size: 5, want: [][]int{{1, 2}}},
+		{name: "size one", ids: []int{1, 2, 3}, size: 1, want: [][]int{{1}, {2}, {3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkIDs(tt.ids, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("chunkIDs(%v, %d) = %v, want %v", tt.ids, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateBulkResultIsIndexAligned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req StoryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Name == "bad" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Story{Name: req.Name})
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("token", WithBaseURL(srv.URL+"/"), WithRetryPolicy(&RetryPolicy{}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	stories := []*StoryRequest{
+		{Name: "first"},
+		{Name: "bad"},
+		{Name: "third"},
+	}
+	result := client.Stories.CreateBulk(1, stories, WithConcurrency(1))
+
+	if len(result.Stories) != len(stories) {
+		t.Fatalf("len(result.Stories) = %d, want %d", len(result.Stories), len(stories))
+	}
+	if result.Stories[0] == nil || result.Stories[0].Name != "first" {
+		t.Fatalf("result.Stories[0] = %+v, want a Story named %q", result.Stories[0], "first")
+	}
+	if result.Stories[1] != nil {
+		t.Fatalf("result.Stories[1] = %+v, want nil for the failed request", result.Stories[1])
+	}
+	if result.Stories[2] == nil || result.Stories[2].Name != "third" {
+		t.Fatalf("result.Stories[2] = %+v, want a Story named %q", result.Stories[2], "third")
+	}
+	if _, ok := result.Errors[1]; !ok {
+		t.Fatal("result.Errors[1] missing for the failed request")
+	}
+}
+
+func TestResolveBulkConfigClampsNonPositiveValues(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []BulkOption
+		wantChunkSize   int
+		wantConcurrency int
+	}{
+		{name: "no options", opts: nil, wantChunkSize: bulkChunkSize, wantConcurrency: defaultBulkConcurrency},
+		{name: "overridden", opts: []BulkOption{WithChunkSize(10), WithConcurrency(2)}, wantChunkSize: 10, wantConcurrency: 2},
+		{name: "zero values", opts: []BulkOption{WithChunkSize(0), WithConcurrency(0)}, wantChunkSize: bulkChunkSize, wantConcurrency: 1},
+		{name: "negative values", opts: []BulkOption{WithChunkSize(-1), WithConcurrency(-1)}, wantChunkSize: bulkChunkSize, wantConcurrency: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := resolveBulkConfig(tt.opts)
+			if cfg.chunkSize != tt.wantChunkSize {
+				t.Errorf("chunkSize = %d, want %d", cfg.chunkSize, tt.wantChunkSize)
+			}
+			if cfg.concurrency != tt.wantConcurrency {
+				t.Errorf("concurrency = %d, want %d", cfg.concurrency, tt.wantConcurrency)
+			}
+		})
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseURL   = "https://www.pivotaltracker.com/services/v5/"
+	defaultUserAgent = "go-pivotaltracker"
+)
+
+// Logger is the interface used by the Client to report retried requests.
+// *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// RateLimiter is consulted before every request is sent, giving callers a
+// place to enforce Pivotal Tracker's API rate limits.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RetryPolicy configures the retryable transport installed by NewClient.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried after
+	// the initial attempt. Zero disables retrying.
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff with jitter
+	// used when the response carries no Retry-After header.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used when none is supplied
+// via WithRetryPolicy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// Client manages communication with the Pivotal Tracker API.
+type Client struct {
+	httpClient  *http.Client
+	token       string
+	baseURL     *url.URL
+	userAgent   string
+	rateLimiter RateLimiter
+
+	Stories  *StoryService
+	Epics    *EpicService
+	Tasks    *TaskService
+	Comments *CommentService
+	Blockers *BlockerService
+}
+
+// ClientOption configures a Client produced by NewClient.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	httpClient  *http.Client
+	baseURL     string
+	userAgent   string
+	retryPolicy *RetryPolicy
+	rateLimiter RateLimiter
+	logger      Logger
+}
+
+// WithHTTPClient sets the *http.Client used to send requests. Its
+// Transport, if any, is wrapped with the retryable transport rather than
+// replaced.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the default Pivotal Tracker API base URL, which is
+// mostly useful for pointing the client at a test server.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.baseURL = baseURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy configures the backoff and retry cap used by the
+// retryable transport. Passing a policy with MaxRetries of 0 disables
+// retrying.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter installs a RateLimiter that is consulted before every
+// request is sent.
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.rateLimiter = limiter
+	}
+}
+
+// WithLogger installs a Logger used to report retried requests.
+func WithLogger(logger Logger) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.logger = logger
+	}
+}
+
+// NewClient returns a new Client for accessing the Pivotal Tracker API
+// using the given API token. Options are applied in order, so later
+// options take precedence over earlier ones.
+func NewClient(token string, opts ...ClientOption) (*Client, error) {
+	cfg := &clientConfig{
+		httpClient:  http.DefaultClient,
+		baseURL:     defaultBaseURL,
+		userAgent:   defaultUserAgent,
+		retryPolicy: DefaultRetryPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	baseURL, err := url.Parse(cfg.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("pivotal: invalid base URL: %v", err)
+	}
+
+	httpClient := *cfg.httpClient
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if cfg.retryPolicy != nil && cfg.retryPolicy.MaxRetries > 0 {
+		transport = &retryTransport{
+			next:   transport,
+			policy: cfg.retryPolicy,
+			logger: cfg.logger,
+		}
+	}
+	httpClient.Transport = transport
+
+	client := &Client{
+		httpClient:  &httpClient,
+		token:       token,
+		baseURL:     baseURL,
+		userAgent:   cfg.userAgent,
+		rateLimiter: cfg.rateLimiter,
+	}
+
+	client.Stories = newStoryService(client)
+	client.Epics = newEpicService(client)
+	client.Tasks = newTaskService(client)
+	client.Comments = newCommentService(client)
+	client.Blockers = newBlockerService(client)
+
+	return client, nil
+}
+
+// NewRequest creates an API request against urlStr, which is resolved
+// relative to the client's base URL. body, if non-nil, is JSON-encoded
+// and used as the request body.
+func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+	rel, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	u := c.baseURL.ResolveReference(rel)
+
+	var buf io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		buf = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-TrackerToken", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// NewUploadRequest creates a multipart/form-data POST request against
+// urlStr, resolved relative to the client's base URL, attaching the
+// contents of r as a file named filename under the given form field.
+func (c *Client) NewUploadRequest(urlStr, fieldName, filename string, r io.Reader) (*http.Request, error) {
+	rel, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	u := c.baseURL.ResolveReference(rel)
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile(fieldName, filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-TrackerToken", c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// Do sends req and decodes the JSON response body into v, unless v is nil.
+func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp, fmt.Errorf("pivotal: %v %v: %v: %s", req.Method, req.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil && err != io.EOF {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+// retryTransport wraps an http.RoundTripper, retrying requests that fail
+// with a 429 or 5xx response using exponential backoff with jitter, or the
+// delay given by the response's Retry-After header when present.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy *RetryPolicy
+	logger Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if bodyBytes != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || !isRetryable(resp) || attempt >= t.policy.MaxRetries {
+			return resp, err
+		}
+
+		wait := retryDelay(resp, t.policy, attempt)
+		if t.logger != nil {
+			t.logger.Printf("pivotal: %v %v returned %v, retrying in %v (attempt %d/%d)",
+				req.Method, req.URL, resp.Status, wait, attempt+1, t.policy.MaxRetries)
+		}
+
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetryable(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay returns how long to wait before the next attempt, honoring the
+// response's Retry-After header (delta-seconds or HTTP-date) when present
+// and otherwise falling back to exponential backoff with jitter.
+func retryDelay(resp *http.Response, policy *RetryPolicy, attempt int) time.Duration {
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return d
+	}
+
+	backoff := policy.MinBackoff * time.Duration(1<<uint(attempt))
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
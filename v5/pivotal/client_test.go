@@ -0,0 +1,136 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeRateLimiter struct {
+	waited bool
+	err    error
+}
+
+func (l *fakeRateLimiter) Wait(ctx context.Context) error {
+	l.waited = true
+	return l.err
+}
+
+func TestClientDoConsultsRateLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	limiter := &fakeRateLimiter{}
+	client, err := NewClient("token", WithBaseURL(srv.URL+"/"), WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	req, err := client.NewRequest("GET", "stories/1", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !limiter.waited {
+		t.Fatal("Do did not consult the configured RateLimiter")
+	}
+}
+
+func TestClientDoPropagatesRateLimiterError(t *testing.T) {
+	wantErr := errors.New("rate limited")
+	client, err := NewClient("token", WithRateLimiter(&fakeRateLimiter{err: wantErr}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	req, err := client.NewRequest("GET", "stories/1", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if _, err := client.Do(req, nil); !errors.Is(err, wantErr) {
+		t.Fatalf("Do err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantOK  bool
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "empty", value: "", wantOK: false},
+		{name: "delta seconds", value: "2", wantOK: true, wantMin: 2 * time.Second, wantMax: 2 * time.Second},
+		{name: "negative delta seconds", value: "-1", wantOK: false},
+		{name: "http date in the past", value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), wantOK: true, wantMin: 0, wantMax: 0},
+		{name: "not a number or date", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := parseRetryAfter(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("parseRetryAfter(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if ok && (d < tt.wantMin || d > tt.wantMax) {
+				t.Fatalf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, d, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRetryDelayHonorsRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+	policy := DefaultRetryPolicy()
+
+	d := retryDelay(resp, policy, 0)
+	if d != 5*time.Second {
+		t.Fatalf("retryDelay = %v, want 5s", d)
+	}
+}
+
+func TestRetryDelayBacksOffWithinBounds(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		d := retryDelay(resp, policy, attempt)
+		if d < 0 || d > policy.MaxBackoff {
+			t.Fatalf("retryDelay(attempt=%d) = %v, want between 0 and %v", attempt, d, policy.MaxBackoff)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		want bool
+	}{
+		{name: "nil response", resp: nil, want: false},
+		{name: "429", resp: &http.Response{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "500", resp: &http.Response{StatusCode: http.StatusInternalServerError}, want: true},
+		{name: "200", resp: &http.Response{StatusCode: http.StatusOK}, want: false},
+		{name: "404", resp: &http.Response{StatusCode: http.StatusNotFound}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.resp); got != tt.want {
+				t.Fatalf("isRetryable(%+v) = %v, want %v", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// CommentRequest is used to create or update a Comment.
+type CommentRequest struct {
+	Text              string `json:"text,omitempty"`
+	FileAttachmentIds []int  `json:"file_attachment_ids,omitempty"`
+}
+
+// Attachment is a file uploaded to Pivotal Tracker and referenced from a
+// comment via its FileAttachmentIds.
+type Attachment struct {
+	Id          int        `json:"id,omitempty"`
+	Filename    string     `json:"filename,omitempty"`
+	ContentType string     `json:"content_type,omitempty"`
+	Size        int        `json:"size,omitempty"`
+	UploadedAt  *time.Time `json:"uploaded_at,omitempty"`
+	URL         string     `json:"url,omitempty"`
+}
+
+// CommentService wraps the client context to operate on the comments
+// belonging to a single story.
+type CommentService struct {
+	client *Client
+}
+
+func newCommentService(client *Client) *CommentService {
+	return &CommentService{client}
+}
+
+func newCommentsRequestFunc(client *Client, projectId, storyId int) func() *http.Request {
+	return func() *http.Request {
+		u := fmt.Sprintf("projects/%v/stories/%v/comments", projectId, storyId)
+		req, _ := client.NewRequest("GET", u, nil)
+		return req
+	}
+}
+
+// List returns all the comments belonging to the given story.
+func (service *CommentService) List(projectId, storyId int) ([]*Comment, error) {
+	reqFunc := newCommentsRequestFunc(service.client, projectId, storyId)
+	cursor, err := newCursor(service.client, reqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*Comment
+	if err := cursor.all(&comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ListContext is the same as List, but it carries ctx for cancellation and
+// deadlines.
+func (service *CommentService) ListContext(ctx context.Context, projectId, storyId int) ([]*Comment, error) {
+	reqFunc := newCommentsRequestFunc(service.client, projectId, storyId)
+	ctxReqFunc := func() *http.Request { return reqFunc().WithContext(ctx) }
+	cursor, err := newCursor(service.client, ctxReqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*Comment
+	if err := cursor.all(&comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CommentCursor is used to implement the iterator pattern over comments.
+type CommentCursor struct {
+	*cursor
+	buff []*Comment
+}
+
+// Next returns the next comment.
+//
+// In case there are no more comments, io.EOF is returned as an error.
+func (c *CommentCursor) Next() (comment *Comment, err error) {
+	if len(c.buff) == 0 {
+		_, err = c.next(&c.buff)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.buff) == 0 {
+		err = io.EOF
+	} else {
+		comment, c.buff = c.buff[0], c.buff[1:]
+	}
+	return comment, err
+}
+
+// Iterate returns a cursor that can be used to iterate over the comments
+// belonging to the given story. More comments are fetched on demand as
+// needed.
+func (service *CommentService) Iterate(projectId, storyId int) (*CommentCursor, error) {
+	reqFunc := newCommentsRequestFunc(service.client, projectId, storyId)
+	cursor, err := newCursor(service.client, reqFunc, PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &CommentCursor{cursor, make([]*Comment, 0)}, nil
+}
+
+// IterateContext is the same as Iterate, but it carries ctx for cancellation
+// and deadlines.
+func (service *CommentService) IterateContext(ctx context.Context, projectId, storyId int) (*CommentCursor, error) {
+	reqFunc := newCommentsRequestFunc(service.client, projectId, storyId)
+	ctxReqFunc := func() *http.Request { return reqFunc().WithContext(ctx) }
+	cursor, err := newCursor(service.client, ctxReqFunc, PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &CommentCursor{cursor, make([]*Comment, 0)}, nil
+}
+
+// Get returns a single comment by ID.
+func (service *CommentService) Get(projectId, storyId, commentId int) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments/%v", projectId, storyId, commentId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var comment Comment
+	resp, err := service.client.Do(req, &comment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &comment, resp, nil
+}
+
+// GetContext is the same as Get, but it carries ctx for cancellation and
+// deadlines.
+func (service *CommentService) GetContext(ctx context.Context, projectId, storyId, commentId int) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments/%v", projectId, storyId, commentId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var comment Comment
+	resp, err := service.client.Do(req, &comment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &comment, resp, nil
+}
+
+// Create adds a new comment to the given story.
+func (service *CommentService) Create(projectId, storyId int, comment *CommentRequest) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments", projectId, storyId)
+	req, err := service.client.NewRequest("POST", u, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var newComment Comment
+	resp, err := service.client.Do(req, &newComment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newComment, resp, nil
+}
+
+// CreateContext is the same as Create, but it carries ctx for cancellation
+// and deadlines.
+func (service *CommentService) CreateContext(ctx context.Context, projectId, storyId int, comment *CommentRequest) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments", projectId, storyId)
+	req, err := service.client.NewRequest("POST", u, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var newComment Comment
+	resp, err := service.client.Do(req, &newComment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newComment, resp, nil
+}
+
+// Update updates an existing comment.
+func (service *CommentService) Update(projectId, storyId, commentId int, comment *CommentRequest) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments/%v", projectId, storyId, commentId)
+	req, err := service.client.NewRequest("PUT", u, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updatedComment Comment
+	resp, err := service.client.Do(req, &updatedComment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedComment, resp, nil
+}
+
+// UpdateContext is the same as Update, but it carries ctx for cancellation
+// and deadlines.
+func (service *CommentService) UpdateContext(ctx context.Context, projectId, storyId, commentId int, comment *CommentRequest) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments/%v", projectId, storyId, commentId)
+	req, err := service.client.NewRequest("PUT", u, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var updatedComment Comment
+	resp, err := service.client.Do(req, &updatedComment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedComment, resp, nil
+}
+
+// Delete removes a comment from the given story.
+func (service *CommentService) Delete(projectId, storyId, commentId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments/%v", projectId, storyId, commentId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.Do(req, nil)
+}
+
+// DeleteContext is the same as Delete, but it carries ctx for cancellation
+// and deadlines.
+func (service *CommentService) DeleteContext(ctx context.Context, projectId, storyId, commentId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/comments/%v", projectId, storyId, commentId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	return service.client.Do(req, nil)
+}
+
+// UploadAttachment uploads r as a file attachment for the given project,
+// returning the resulting Attachment. Pass its Id in a CommentRequest's
+// FileAttachmentIds to attach it to a comment.
+func (service *CommentService) UploadAttachment(projectId int, filename string, r io.Reader) (*Attachment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/uploads", projectId)
+	req, err := service.client.NewUploadRequest(u, "file", filename, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var attachment Attachment
+	resp, err := service.client.Do(req, &attachment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &attachment, resp, nil
+}
+
+// UploadAttachmentContext is the same as UploadAttachment, but it carries
+// ctx for cancellation and deadlines.
+func (service *CommentService) UploadAttachmentContext(ctx context.Context, projectId int, filename string, r io.Reader) (*Attachment, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/uploads", projectId)
+	req, err := service.client.NewUploadRequest(u, "file", filename, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var attachment Attachment
+	resp, err := service.client.Do(req, &attachment)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &attachment, resp, nil
+}
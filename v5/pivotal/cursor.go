@@ -0,0 +1,195 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// IterateOption configures a single Iterate/IterateContext/Stream call.
+type IterateOption func(*iterateConfig)
+
+type iterateConfig struct {
+	pageSize int
+}
+
+// WithPageSize overrides PageLimit for a single Iterate/IterateContext/
+// Stream call.
+func WithPageSize(n int) IterateOption {
+	return func(cfg *iterateConfig) { cfg.pageSize = n }
+}
+
+func resolveIterateConfig(opts []IterateOption) *iterateConfig {
+	cfg := &iterateConfig{pageSize: PageLimit}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.pageSize <= 0 {
+		cfg.pageSize = PageLimit
+	}
+	return cfg
+}
+
+// cursor implements the pagination shared by the various XxxCursor types.
+// It fetches pages of size limit on demand, recording the running offset
+// and the total item count reported by Pivotal Tracker. While the caller
+// consumes the page returned by next, cursor prefetches the following page
+// in the background so the next call to next rarely blocks on a round trip.
+type cursor struct {
+	client  *Client
+	reqFunc func() *http.Request
+	limit   int
+	total   int
+
+	mu      sync.Mutex
+	offset  int
+	fetched int
+	pending *prefetch
+}
+
+// prefetch holds the in-flight result of a background page fetch.
+type prefetch struct {
+	done chan struct{}
+	page interface{}
+	n    int
+	err  error
+}
+
+// newCursor issues a HEAD request built from reqFunc to learn how many
+// items are available, then returns a cursor ready to page through them
+// limit items at a time. limit of 0 means "fetch everything in one page".
+func newCursor(client *Client, reqFunc func() *http.Request, limit int) (*cursor, error) {
+	req := reqFunc()
+	req.Method = "HEAD"
+
+	resp, err := client.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	total, _ := strconv.Atoi(resp.Header.Get("X-Tracker-Pagination-Total"))
+
+	return &cursor{
+		client:  client,
+		reqFunc: reqFunc,
+		limit:   limit,
+		total:   total,
+	}, nil
+}
+
+// Total returns the total number of items the cursor will walk through, as
+// reported by Pivotal Tracker when the cursor was created.
+func (c *cursor) Total() int {
+	return c.total
+}
+
+// Fetched returns how many items have been fetched so far.
+func (c *cursor) Fetched() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetched
+}
+
+// fetchPage fetches the page at offset into v, a pointer to a slice, and
+// returns the number of items decoded into it.
+func (c *cursor) fetchPage(v interface{}, offset int) (int, error) {
+	req := c.reqFunc()
+	q := req.URL.Query()
+	if c.limit != 0 {
+		q.Set("limit", strconv.Itoa(c.limit))
+	}
+	q.Set("offset", strconv.Itoa(offset))
+	req.URL.RawQuery = q.Encode()
+
+	if _, err := c.client.Do(req, v); err != nil {
+		return 0, err
+	}
+	return reflect.ValueOf(v).Elem().Len(), nil
+}
+
+// prefetchNext kicks off a single background fetch of the page following
+// the cursor's current offset, storing it as c.pending for the next call
+// to next to pick up. sample is used only to learn the slice type to
+// allocate for the background fetch.
+func (c *cursor) prefetchNext(sample interface{}) {
+	if c.total != 0 && c.offset >= c.total {
+		return
+	}
+
+	offset := c.offset
+	page := reflect.New(reflect.TypeOf(sample).Elem())
+	p := &prefetch{done: make(chan struct{}), page: page.Interface()}
+
+	go func() {
+		defer close(p.done)
+		n, err := c.fetchPage(p.page, offset)
+		p.n, p.err = n, err
+	}()
+
+	c.pending = p
+}
+
+// next fetches the next page into v, a pointer to a slice, and returns the
+// number of items fetched. It returns io.EOF once the cursor is exhausted.
+func (c *cursor) next(v interface{}) (int, error) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	var n int
+	var err error
+	if pending != nil {
+		<-pending.done
+		n, err = pending.n, pending.err
+		if err == nil && n > 0 {
+			reflect.ValueOf(v).Elem().Set(reflect.ValueOf(pending.page).Elem())
+		}
+	} else {
+		c.mu.Lock()
+		offset := c.offset
+		exhausted := c.total != 0 && offset >= c.total
+		c.mu.Unlock()
+		if exhausted {
+			return 0, io.EOF
+		}
+		n, err = c.fetchPage(v, offset)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+
+	c.mu.Lock()
+	c.offset += n
+	c.fetched += n
+	c.prefetchNext(v)
+	c.mu.Unlock()
+
+	return n, nil
+}
+
+// all fetches every remaining item into v, a pointer to a slice.
+func (c *cursor) all(v interface{}) error {
+	dst := reflect.ValueOf(v).Elem()
+	for {
+		page := reflect.New(dst.Type())
+		_, err := c.next(page.Interface())
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.AppendSlice(dst, page.Elem()))
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type cursorItem struct {
+	ID int `json:"id"`
+}
+
+// newItemsServer returns a test server that paginates a total of n items,
+// honoring the limit/offset query parameters the way Pivotal Tracker does,
+// and reporting total via X-Tracker-Pagination-Total on every response
+// (including HEAD, as newCursor relies on).
+func newItemsServer(t *testing.T, total int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Tracker-Pagination-Total", strconv.Itoa(total))
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit == 0 {
+			limit = total
+		}
+
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+
+		var items []cursorItem
+		for i := offset; i < end; i++ {
+			items = append(items, cursorItem{ID: i})
+		}
+		json.NewEncoder(w).Encode(items)
+	}))
+}
+
+func newTestCursor(t *testing.T, total, limit int) *cursor {
+	t.Helper()
+	srv := newItemsServer(t, total)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient("token", WithBaseURL(srv.URL+"/"), WithRetryPolicy(&RetryPolicy{}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	reqFunc := func() *http.Request {
+		req, _ := client.NewRequest("GET", "items", nil)
+		return req
+	}
+
+	c, err := newCursor(client, reqFunc, limit)
+	if err != nil {
+		t.Fatalf("newCursor: %v", err)
+	}
+	return c
+}
+
+func TestCursorAllFetchesEveryItem(t *testing.T) {
+	c := newTestCursor(t, 25, PageLimit)
+
+	var items []*cursorItem
+	if err := c.all(&items); err != nil {
+		t.Fatalf("all: %v", err)
+	}
+	if len(items) != 25 {
+		t.Fatalf("len(items) = %d, want 25", len(items))
+	}
+	for i, item := range items {
+		if item.ID != i {
+			t.Fatalf("items[%d].ID = %d, want %d", i, item.ID, i)
+		}
+	}
+}
+
+func TestCursorNextReturnsEOFWhenExhausted(t *testing.T) {
+	c := newTestCursor(t, 3, PageLimit)
+
+	var page []*cursorItem
+	if _, err := c.next(&page); err != nil {
+		t.Fatalf("next: %v", err)
+	}
+
+	page = nil
+	if _, err := c.next(&page); err != io.EOF {
+		t.Fatalf("next after exhaustion err = %v, want io.EOF", err)
+	}
+}
+
+func TestResolveIterateConfigClampsNonPositivePageSize(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []IterateOption
+		want int
+	}{
+		{name: "no options", opts: nil, want: PageLimit},
+		{name: "positive page size", opts: []IterateOption{WithPageSize(5)}, want: 5},
+		{name: "zero page size", opts: []IterateOption{WithPageSize(0)}, want: PageLimit},
+		{name: "negative page size", opts: []IterateOption{WithPageSize(-1)}, want: PageLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := resolveIterateConfig(tt.opts)
+			if cfg.pageSize != tt.want {
+				t.Fatalf("pageSize = %d, want %d", cfg.pageSize, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@
 package pivotal
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -62,8 +63,13 @@ func newEpicService(client *Client) *EpicService {
 // to get the right results. Since the response as generated by Pivotal Tracker
 // is not always sorted when using a filter, this approach is required to get
 // the right data. Not sure whether this is a bug or a feature.
-func (service *EpicService) List(projectID int, filter string) ([]*Epic, error) {
-	reqFunc := newEpicsRequestFunc(service.client, projectID, filter)
+func (service *EpicService) List(projectID int, filter *StoryFilter) ([]*Epic, error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reqFunc := newEpicsRequestFunc(service.client, projectID, filterStr)
 	cursor, err := newCursor(service.client, reqFunc, 0)
 	if err != nil {
 		return nil, err
@@ -87,6 +93,35 @@ func newEpicsRequestFunc(client *Client, projectID int, filter string) func() *h
 	}
 }
 
+// ListContext is the same as List, but it carries ctx for cancellation and
+// deadlines. The context is applied to every HTTP request issued while
+// walking the pagination, including the initial count request.
+func (service *EpicService) ListContext(ctx context.Context, projectID int, filter *StoryFilter) ([]*Epic, error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reqFunc := newEpicsRequestFuncContext(ctx, service.client, projectID, filterStr)
+	cursor, err := newCursor(service.client, reqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var epics []*Epic
+	if err := cursor.all(&epics); err != nil {
+		return nil, err
+	}
+	return epics, nil
+}
+
+func newEpicsRequestFuncContext(ctx context.Context, client *Client, projectID int, filter string) func() *http.Request {
+	reqFunc := newEpicsRequestFunc(client, projectID, filter)
+	return func() *http.Request {
+		return reqFunc().WithContext(ctx)
+	}
+}
+
 // EpicCursor is used to implement the iterator pattern.
 type EpicCursor struct {
 	*cursor
@@ -112,17 +147,82 @@ func (c *EpicCursor) Next() (e *Epic, err error) {
 	return e, err
 }
 
-// Iterate returns a cursor that can be used to iterate over the epics specified
-// by the filter. More epics are fetched on demand as needed.
-func (service *EpicService) Iterate(projectID int, filter string) (c *EpicCursor, err error) {
-	reqFunc := newEpicsRequestFunc(service.client, projectID, filter)
-	cursor, err := newCursor(service.client, reqFunc, PageLimit)
+// Iterate returns a cursor that can be used to iterate over the epics
+// specified by the filter. More epics are fetched on demand as needed,
+// PageLimit at a time unless overridden with WithPageSize.
+func (service *EpicService) Iterate(projectID int, filter *StoryFilter, opts ...IterateOption) (c *EpicCursor, err error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := resolveIterateConfig(opts)
+	reqFunc := newEpicsRequestFunc(service.client, projectID, filterStr)
+	cursor, err := newCursor(service.client, reqFunc, cfg.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &EpicCursor{cursor, make([]*Epic, 0)}, nil
+}
+
+// IterateContext is the same as Iterate, but it carries ctx for cancellation
+// and deadlines. Once ctx is done, any page fetch the cursor makes, including
+// one prefetched in the background, is aborted and Next returns an error
+// wrapping ctx.Err(); epics already fetched are still returned.
+func (service *EpicService) IterateContext(ctx context.Context, projectID int, filter *StoryFilter, opts ...IterateOption) (c *EpicCursor, err error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := resolveIterateConfig(opts)
+	reqFunc := newEpicsRequestFuncContext(ctx, service.client, projectID, filterStr)
+	cursor, err := newCursor(service.client, reqFunc, cfg.pageSize)
 	if err != nil {
 		return nil, err
 	}
 	return &EpicCursor{cursor, make([]*Epic, 0)}, nil
 }
 
+// Stream returns a channel of epics matching filter and a channel that
+// receives at most one error. Both channels are closed once the stream is
+// exhausted, an error occurs, or ctx is done.
+func (service *EpicService) Stream(ctx context.Context, projectID int, filter *StoryFilter, opts ...IterateOption) (<-chan *Epic, <-chan error) {
+	out := make(chan *Epic)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		cursor, err := service.IterateContext(ctx, projectID, filter, opts...)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		for {
+			epic, err := cursor.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			select {
+			case out <- epic:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return out, errc
+}
+
 // Create is used to create a new Epic with an EpicRequest.
 func (service *EpicService) Create(projectID int, epic *EpicRequest) (*Epic, *http.Response, error) {
 	if projectID == 0 {
@@ -149,6 +249,34 @@ func (service *EpicService) Create(projectID int, epic *EpicRequest) (*Epic, *ht
 	return &newEpic, resp, nil
 }
 
+// CreateContext is the same as Create, but it carries ctx for cancellation
+// and deadlines.
+func (service *EpicService) CreateContext(ctx context.Context, projectID int, epic *EpicRequest) (*Epic, *http.Response, error) {
+	if projectID == 0 {
+		return nil, nil, &ErrFieldNotSet{"project_id"}
+	}
+
+	if epic.Name == "" {
+		return nil, nil, &ErrFieldNotSet{"name"}
+	}
+
+	u := fmt.Sprintf("projects/%v/epics", projectID)
+	req, err := service.client.NewRequest("POST", u, epic)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var newEpic Epic
+
+	resp, err := service.client.Do(req, &newEpic)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newEpic, resp, nil
+}
+
 // Get is returns an Epic by ID.
 func (service *EpicService) Get(projectID, epicID int) (*Epic, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/epics/%v", projectID, epicID)
@@ -166,13 +294,52 @@ func (service *EpicService) Get(projectID, epicID int) (*Epic, *http.Response, e
 	return &epic, resp, err
 }
 
+// GetContext is the same as Get, but it carries ctx for cancellation and
+// deadlines.
+func (service *EpicService) GetContext(ctx context.Context, projectID, epicID int) (*Epic, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/epics/%v", projectID, epicID)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var epic Epic
+	resp, err := service.client.Do(req, &epic)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &epic, resp, err
+}
+
 // Update is will update an Epic with an EpicRequest.
 func (service *EpicService) Update(projectID, epicID int, epic *EpicRequest) (*Epic, *http.Response, error) {
-	u := fmt.Sprintf("projects/%v/stories/%v", projectID, epicID)
+	u := fmt.Sprintf("projects/%v/epics/%v", projectID, epicID)
+	req, err := service.client.NewRequest("PUT", u, epic)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updatedEpic Epic
+	resp, err := service.client.Do(req, &updatedEpic)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedEpic, resp, err
+
+}
+
+// UpdateContext is the same as Update, but it carries ctx for cancellation
+// and deadlines.
+func (service *EpicService) UpdateContext(ctx context.Context, projectID, epicID int, epic *EpicRequest) (*Epic, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/epics/%v", projectID, epicID)
 	req, err := service.client.NewRequest("PUT", u, epic)
 	if err != nil {
 		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
 	var updatedEpic Epic
 	resp, err := service.client.Do(req, &updatedEpic)
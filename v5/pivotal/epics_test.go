@@ -0,0 +1,60 @@
+// Copyright (c) 2018 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEpicServiceUpdateRequestsEpicsPath(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("token", WithBaseURL(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, _, err := client.Epics.Update(1, 2, &EpicRequest{Name: "renamed"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	want := "/projects/1/epics/2"
+	if gotPath != want {
+		t.Fatalf("Update requested path %q, want %q", gotPath, want)
+	}
+}
+
+func TestEpicServiceUpdateContextRequestsEpicsPath(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("token", WithBaseURL(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, _, err := client.Epics.UpdateContext(context.Background(), 1, 2, &EpicRequest{Name: "renamed"}); err != nil {
+		t.Fatalf("UpdateContext: %v", err)
+	}
+
+	want := "/projects/1/epics/2"
+	if gotPath != want {
+		t.Fatalf("UpdateContext requested path %q, want %q", gotPath, want)
+	}
+}
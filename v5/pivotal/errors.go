@@ -0,0 +1,17 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import "fmt"
+
+// ErrFieldNotSet is returned by request constructors when a field required
+// by the Pivotal Tracker API was left at its zero value.
+type ErrFieldNotSet struct {
+	FieldName string
+}
+
+func (err *ErrFieldNotSet) Error() string {
+	return fmt.Sprintf("field not set: %v", err.FieldName)
+}
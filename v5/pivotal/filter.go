@@ -0,0 +1,192 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var validStoryStates = map[string]bool{
+	StoryStateUnscheduled: true,
+	StoryStatePlanned:     true,
+	StoryStateUnstarted:   true,
+	StoryStateStarted:     true,
+	StoryStateFinished:    true,
+	StoryStateDelivered:   true,
+	StoryStateAccepted:    true,
+	StoryStateRejected:    true,
+}
+
+var validStoryTypes = map[string]bool{
+	StoryTypeFeature: true,
+	StoryTypeBug:     true,
+	StoryTypeChore:   true,
+	StoryTypeRelease: true,
+}
+
+// StoryFilter builds a Pivotal Tracker search filter string term by term,
+// as documented at https://www.pivotaltracker.com/help/articles/advanced_search/.
+// It is used in place of a raw filter string with StoryService.List,
+// StoryService.Iterate, EpicService.List and EpicService.Iterate.
+type StoryFilter struct {
+	terms []string
+	err   error
+}
+
+// Filter returns an empty StoryFilter ready to be narrowed down with its
+// builder methods.
+func Filter() *StoryFilter {
+	return &StoryFilter{}
+}
+
+// Build returns the Pivotal Tracker filter string that List/Iterate send for
+// this filter, or the first validation error recorded by a builder method.
+func (f *StoryFilter) Build() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return strings.Join(f.terms, " "), nil
+}
+
+func (f *StoryFilter) fail(err error) *StoryFilter {
+	if f.err == nil {
+		f.err = err
+	}
+	return f
+}
+
+func (f *StoryFilter) term(s string) *StoryFilter {
+	f.terms = append(f.terms, s)
+	return f
+}
+
+// States restricts the filter to stories in any of the given states. Each
+// state must be one of the StoryState* constants.
+func (f *StoryFilter) States(states ...string) *StoryFilter {
+	for _, state := range states {
+		if !validStoryStates[state] {
+			return f.fail(fmt.Errorf("pivotal: invalid story state: %q", state))
+		}
+	}
+	return f.term("state:" + strings.Join(states, ","))
+}
+
+// Types restricts the filter to stories of any of the given types. Each
+// type must be one of the StoryType* constants.
+func (f *StoryFilter) Types(types ...string) *StoryFilter {
+	for _, typ := range types {
+		if !validStoryTypes[typ] {
+			return f.fail(fmt.Errorf("pivotal: invalid story type: %q", typ))
+		}
+	}
+	return f.term("type:" + strings.Join(types, ","))
+}
+
+// Labels restricts the filter to stories carrying any of the given labels.
+// Label values containing whitespace are quoted automatically.
+func (f *StoryFilter) Labels(labels ...string) *StoryFilter {
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = quoteFilterValue(label)
+	}
+	return f.term("label:" + strings.Join(quoted, ","))
+}
+
+// Owner restricts the filter to stories owned by the given person, which
+// may be "me" or a full name.
+func (f *StoryFilter) Owner(owner string) *StoryFilter {
+	return f.term("owner:" + quoteFilterValue(owner))
+}
+
+// Requester restricts the filter to stories requested by the given person,
+// which may be "me" or a full name.
+func (f *StoryFilter) Requester(requester string) *StoryFilter {
+	return f.term("requester:" + quoteFilterValue(requester))
+}
+
+// EstimateGT restricts the filter to stories estimated higher than n points.
+func (f *StoryFilter) EstimateGT(n float64) *StoryFilter {
+	return f.term("estimate:>" + formatEstimate(n))
+}
+
+// EstimateLT restricts the filter to stories estimated lower than n points.
+func (f *StoryFilter) EstimateLT(n float64) *StoryFilter {
+	return f.term("estimate:<" + formatEstimate(n))
+}
+
+// Estimate restricts the filter to stories estimated at exactly n points.
+func (f *StoryFilter) Estimate(n float64) *StoryFilter {
+	return f.term("estimate:" + formatEstimate(n))
+}
+
+// ModifiedSince restricts the filter to stories modified on or after t.
+func (f *StoryFilter) ModifiedSince(t time.Time) *StoryFilter {
+	return f.term("modified_since:" + t.Format("01/02/2006"))
+}
+
+// ModifiedBefore restricts the filter to stories modified before t.
+func (f *StoryFilter) ModifiedBefore(t time.Time) *StoryFilter {
+	return f.term("modified_before:" + t.Format("01/02/2006"))
+}
+
+// IncludeDone includes stories in the "done" panel (accepted before the
+// current iteration) when include is true.
+func (f *StoryFilter) IncludeDone(include bool) *StoryFilter {
+	return f.term("includedone:" + strconv.FormatBool(include))
+}
+
+// And combines the given filters into one, ANDing all of their terms
+// together. The first validation error among them, if any, is propagated.
+func And(filters ...*StoryFilter) *StoryFilter {
+	combined := Filter()
+	for _, f := range filters {
+		if f.err != nil {
+			return combined.fail(f.err)
+		}
+		combined.terms = append(combined.terms, f.terms...)
+	}
+	return combined
+}
+
+// Or groups the given filters so that a story matching any one of them is
+// included, e.g. "(state:started OR state:finished)".
+func Or(filters ...*StoryFilter) *StoryFilter {
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		s, err := f.Build()
+		if err != nil {
+			return Filter().fail(err)
+		}
+		parts[i] = s
+	}
+	return Filter().term("(" + strings.Join(parts, " OR ") + ")")
+}
+
+// Not negates every term of filter, e.g. Not(Filter().States(StoryStateAccepted))
+// produces "-state:accepted".
+func Not(filter *StoryFilter) *StoryFilter {
+	if filter.err != nil {
+		return Filter().fail(filter.err)
+	}
+	negated := Filter()
+	for _, term := range filter.terms {
+		negated.terms = append(negated.terms, "-"+term)
+	}
+	return negated
+}
+
+func formatEstimate(n float64) string {
+	return strconv.FormatFloat(n, 'g', -1, 64)
+}
+
+func quoteFilterValue(s string) string {
+	if strings.ContainsAny(s, " \t\n\v\f\r\"") {
+		return strconv.Quote(s)
+	}
+	return s
+}
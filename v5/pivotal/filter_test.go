@@ -0,0 +1,99 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import "testing"
+
+func TestQuoteFilterValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "me", want: "me"},
+		{in: "John Doe", want: `"John Doe"`},
+		{in: "tab\there", want: "\"tab\\there\""},
+	}
+
+	for _, tt := range tests {
+		if got := quoteFilterValue(tt.in); got != tt.want {
+			t.Errorf("quoteFilterValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStoryFilterBuild(t *testing.T) {
+	got, err := Filter().States(StoryStateStarted, StoryStateFinished).Owner("John Doe").Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := `state:started,finished owner:"John Doe"`
+	if got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestStoryFilterBuildPropagatesFirstError(t *testing.T) {
+	_, err := Filter().States("bogus").Types("bogus").Build()
+	if err == nil {
+		t.Fatal("Build did not return an error for an invalid state")
+	}
+	if got := err.Error(); got != `pivotal: invalid story state: "bogus"` {
+		t.Errorf("Build() err = %q, want the first validation error", got)
+	}
+}
+
+func TestAndCombinesTerms(t *testing.T) {
+	got, err := And(Filter().States(StoryStateStarted), Filter().Owner("me")).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := "state:started owner:me"
+	if got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestAndPropagatesError(t *testing.T) {
+	_, err := And(Filter().States("bogus")).Build()
+	if err == nil {
+		t.Fatal("And did not propagate the invalid state error")
+	}
+}
+
+func TestOrGroupsFilters(t *testing.T) {
+	got, err := Or(Filter().States(StoryStateStarted), Filter().States(StoryStateFinished)).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := "(state:started OR state:finished)"
+	if got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestOrPropagatesError(t *testing.T) {
+	_, err := Or(Filter().States("bogus")).Build()
+	if err == nil {
+		t.Fatal("Or did not propagate the invalid state error")
+	}
+}
+
+func TestNotNegatesTerms(t *testing.T) {
+	got, err := Not(Filter().States(StoryStateAccepted)).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := "-state:accepted"
+	if got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestNotPropagatesError(t *testing.T) {
+	_, err := Not(Filter().States("bogus")).Build()
+	if err == nil {
+		t.Fatal("Not did not propagate the invalid state error")
+	}
+}
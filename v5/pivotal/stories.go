@@ -6,6 +6,7 @@
 package pivotal
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -148,8 +149,13 @@ func newStoryService(client *Client) *StoryService {
 // to get the right results. Since the response as generated by Pivotal Tracker
 // is not always sorted when using a filter, this approach is required to get
 // the right data. Not sure whether this is a bug or a feature.
-func (service *StoryService) List(projectId int, filter string) ([]*Story, error) {
-	reqFunc := newStoriesRequestFunc(service.client, projectId, filter)
+func (service *StoryService) List(projectId int, filter *StoryFilter) ([]*Story, error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reqFunc := newStoriesRequestFunc(service.client, projectId, filterStr)
 	cursor, err := newCursor(service.client, reqFunc, 0)
 	if err != nil {
 		return nil, err
@@ -162,6 +168,15 @@ func (service *StoryService) List(projectId int, filter string) ([]*Story, error
 	return stories, nil
 }
 
+// buildFilter renders filter to the raw filter string expected by the
+// Pivotal Tracker search API. A nil filter means "no filter".
+func buildFilter(filter *StoryFilter) (string, error) {
+	if filter == nil {
+		return "", nil
+	}
+	return filter.Build()
+}
+
 func newStoriesRequestFunc(client *Client, projectId int, filter string) func() *http.Request {
 	return func() *http.Request {
 		u := fmt.Sprintf("projects/%v/stories", projectId)
@@ -173,6 +188,35 @@ func newStoriesRequestFunc(client *Client, projectId int, filter string) func()
 	}
 }
 
+// ListContext is the same as List, but it carries ctx for cancellation and
+// deadlines. The context is applied to every HTTP request issued while
+// walking the pagination, including the initial count request.
+func (service *StoryService) ListContext(ctx context.Context, projectId int, filter *StoryFilter) ([]*Story, error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reqFunc := newStoriesRequestFuncContext(ctx, service.client, projectId, filterStr)
+	cursor, err := newCursor(service.client, reqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var stories []*Story
+	if err := cursor.all(&stories); err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+func newStoriesRequestFuncContext(ctx context.Context, client *Client, projectId int, filter string) func() *http.Request {
+	reqFunc := newStoriesRequestFunc(client, projectId, filter)
+	return func() *http.Request {
+		return reqFunc().WithContext(ctx)
+	}
+}
+
 type StoryCursor struct {
 	*cursor
 	buff []*Story
@@ -197,17 +241,82 @@ func (c *StoryCursor) Next() (s *Story, err error) {
 	return s, err
 }
 
-// Iterate returns a cursor that can be used to iterate over the stories specified
-// by the filter. More stories are fetched on demand as needed.
-func (service *StoryService) Iterate(projectId int, filter string) (c *StoryCursor, err error) {
-	reqFunc := newStoriesRequestFunc(service.client, projectId, filter)
-	cursor, err := newCursor(service.client, reqFunc, PageLimit)
+// Iterate returns a cursor that can be used to iterate over the stories
+// specified by the filter. More stories are fetched on demand as needed,
+// PageLimit at a time unless overridden with WithPageSize.
+func (service *StoryService) Iterate(projectId int, filter *StoryFilter, opts ...IterateOption) (c *StoryCursor, err error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := resolveIterateConfig(opts)
+	reqFunc := newStoriesRequestFunc(service.client, projectId, filterStr)
+	cursor, err := newCursor(service.client, reqFunc, cfg.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &StoryCursor{cursor, make([]*Story, 0)}, nil
+}
+
+// IterateContext is the same as Iterate, but it carries ctx for cancellation
+// and deadlines. Once ctx is done, any page fetch the cursor makes, including
+// one prefetched in the background, is aborted and Next returns an error
+// wrapping ctx.Err(); stories already fetched are still returned.
+func (service *StoryService) IterateContext(ctx context.Context, projectId int, filter *StoryFilter, opts ...IterateOption) (c *StoryCursor, err error) {
+	filterStr, err := buildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := resolveIterateConfig(opts)
+	reqFunc := newStoriesRequestFuncContext(ctx, service.client, projectId, filterStr)
+	cursor, err := newCursor(service.client, reqFunc, cfg.pageSize)
 	if err != nil {
 		return nil, err
 	}
 	return &StoryCursor{cursor, make([]*Story, 0)}, nil
 }
 
+// Stream returns a channel of stories matching filter and a channel that
+// receives at most one error. Both channels are closed once the stream is
+// exhausted, an error occurs, or ctx is done.
+func (service *StoryService) Stream(ctx context.Context, projectId int, filter *StoryFilter, opts ...IterateOption) (<-chan *Story, <-chan error) {
+	out := make(chan *Story)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		cursor, err := service.IterateContext(ctx, projectId, filter, opts...)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		for {
+			story, err := cursor.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			select {
+			case out <- story:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return out, errc
+}
+
 func (service *StoryService) Create(projectId int, story *StoryRequest) (*Story, *http.Response, error) {
 	if projectId == 0 {
 		return nil, nil, &ErrFieldNotSet{"project_id"}
@@ -233,6 +342,34 @@ func (service *StoryService) Create(projectId int, story *StoryRequest) (*Story,
 	return &newStory, resp, nil
 }
 
+// CreateContext is the same as Create, but it carries ctx for cancellation
+// and deadlines.
+func (service *StoryService) CreateContext(ctx context.Context, projectId int, story *StoryRequest) (*Story, *http.Response, error) {
+	if projectId == 0 {
+		return nil, nil, &ErrFieldNotSet{"project_id"}
+	}
+
+	if story.Name == "" {
+		return nil, nil, &ErrFieldNotSet{"name"}
+	}
+
+	u := fmt.Sprintf("projects/%v/stories", projectId)
+	req, err := service.client.NewRequest("POST", u, story)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var newStory Story
+
+	resp, err := service.client.Do(req, &newStory)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newStory, resp, nil
+}
+
 func (service *StoryService) Get(projectId, storyId int) (*Story, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/stories/%v", projectId, storyId)
 	req, err := service.client.NewRequest("GET", u, nil)
@@ -249,6 +386,25 @@ func (service *StoryService) Get(projectId, storyId int) (*Story, *http.Response
 	return &story, resp, err
 }
 
+// GetContext is the same as Get, but it carries ctx for cancellation and
+// deadlines.
+func (service *StoryService) GetContext(ctx context.Context, projectId, storyId int) (*Story, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v", projectId, storyId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var story Story
+	resp, err := service.client.Do(req, &story)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &story, resp, err
+}
+
 func arrayToString(a []int, delim string) string {
 	return strings.Trim(strings.Replace(fmt.Sprint(a), " ", delim, -1), "[]")
 }
@@ -292,32 +448,46 @@ func (service *StoryService) Update(projectId, storyId int, story *StoryRequest)
 
 }
 
-func (service *StoryService) ListTasks(projectId, storyId int) ([]*Task, *http.Response, error) {
-	u := fmt.Sprintf("projects/%v/stories/%v/tasks", projectId, storyId)
-	req, err := service.client.NewRequest("GET", u, nil)
+// UpdateContext is the same as Update, but it carries ctx for cancellation
+// and deadlines.
+func (service *StoryService) UpdateContext(ctx context.Context, projectId, storyId int, story *StoryRequest) (*Story, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v", projectId, storyId)
+	req, err := service.client.NewRequest("PUT", u, story)
 	if err != nil {
 		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
-	var tasks []*Task
-	resp, err := service.client.Do(req, &tasks)
+	var bodyStory Story
+	resp, err := service.client.Do(req, &bodyStory)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return tasks, resp, err
+	return &bodyStory, resp, err
+
 }
 
-func (service *StoryService) AddTask(projectId, storyId int, task *Task) (*http.Response, error) {
-	if task.Description == "" {
-		return nil, &ErrFieldNotSet{"description"}
+// Delete removes a story from its project.
+func (service *StoryService) Delete(projectId, storyId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v", projectId, storyId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	u := fmt.Sprintf("projects/%v/stories/%v/tasks", projectId, storyId)
-	req, err := service.client.NewRequest("POST", u, task)
+	return service.client.Do(req, nil)
+}
+
+// DeleteContext is the same as Delete, but it carries ctx for cancellation
+// and deadlines.
+func (service *StoryService) DeleteContext(ctx context.Context, projectId, storyId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v", projectId, storyId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	return service.client.Do(req, nil)
 }
@@ -338,99 +508,21 @@ func (service *StoryService) ListOwners(projectId, storyId int) ([]*Person, *htt
 	return owners, resp, err
 }
 
-func (service *StoryService) AddComment(
-	projectId int,
-	storyId int,
-	comment *Comment,
-) (*Comment, *http.Response, error) {
-
-	u := fmt.Sprintf("projects/%v/stories/%v/comments", projectId, storyId)
-	req, err := service.client.NewRequest("POST", u, comment)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var newComment Comment
-	resp, err := service.client.Do(req, &newComment)
-	if err != nil {
-		return nil, resp, err
-	}
-
-	return &newComment, resp, err
-}
-
-// ListComments returns the list of Comments in a Story.
-func (service *StoryService) ListComments(
-	projectId int,
-	storyId int,
-) ([]*Comment, *http.Response, error) {
-
-	u := fmt.Sprintf("projects/%v/stories/%v/comments", projectId, storyId)
-	req, err := service.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var comments []*Comment
-	resp, err := service.client.Do(req, &comments)
-	if err != nil {
-		return nil, resp, err
-	}
-
-	return comments, resp, nil
-}
-
-// ListBlockers returns the list of Blockers in a Story.
-func (service *StoryService) ListBlockers(
-	projectId int,
-	storyId int,
-) ([]*Blocker, *http.Response, error) {
-
-	u := fmt.Sprintf("projects/%v/stories/%v/blockers", projectId, storyId)
+// ListOwnersContext is the same as ListOwners, but it carries ctx for
+// cancellation and deadlines.
+func (service *StoryService) ListOwnersContext(ctx context.Context, projectId, storyId int) ([]*Person, *http.Response, error) {
+	u := fmt.Sprintf("projects/%d/stories/%d/owners", projectId, storyId)
 	req, err := service.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
-	var blockers []*Blocker
-	resp, err := service.client.Do(req, &blockers)
-	if err != nil {
-		return nil, resp, err
-	}
-
-	return blockers, resp, nil
-}
-
-func (service *StoryService) AddBlocker(projectId int, storyId int, description string) (*Blocker, *http.Response, error) {
-	u := fmt.Sprintf("projects/%v/stories/%v/blockers", projectId, storyId)
-	req, err := service.client.NewRequest("POST", u, BlockerRequest{
-		Description: description,
-	})
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var blocker Blocker
-	resp, err := service.client.Do(req, &blocker)
-	if err != nil {
-		return nil, resp, err
-	}
-
-	return &blocker, resp, nil
-}
-
-func (service *StoryService) UpdateBlocker(projectId, stroyId, blockerId int, blocker *BlockerRequest) (*Blocker, *http.Response, error) {
-	u := fmt.Sprintf("projects/%v/stories/%v/blockers/%v", projectId, stroyId, blockerId)
-	req, err := service.client.NewRequest("PUT", u, blocker)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var blockerResp Blocker
-	resp, err := service.client.Do(req, &blockerResp)
+	var owners []*Person
+	resp, err := service.client.Do(req, &owners)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return &blockerResp, resp, nil
+	return owners, resp, err
 }
@@ -0,0 +1,32 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStoryServiceGetContextRespectsCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("token", WithBaseURL(srv.URL+"/"), WithRetryPolicy(&RetryPolicy{}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := client.Stories.GetContext(ctx, 1, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetContext err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// TaskRequest is used to create or update a Task.
+type TaskRequest struct {
+	Description string `json:"description,omitempty"`
+	Complete    *bool  `json:"complete,omitempty"`
+	Position    int    `json:"position,omitempty"`
+}
+
+// TaskService wraps the client context to operate on the tasks belonging
+// to a single story.
+type TaskService struct {
+	client *Client
+}
+
+func newTaskService(client *Client) *TaskService {
+	return &TaskService{client}
+}
+
+func newTasksRequestFunc(client *Client, projectId, storyId int) func() *http.Request {
+	return func() *http.Request {
+		u := fmt.Sprintf("projects/%v/stories/%v/tasks", projectId, storyId)
+		req, _ := client.NewRequest("GET", u, nil)
+		return req
+	}
+}
+
+// List returns all the tasks belonging to the given story.
+func (service *TaskService) List(projectId, storyId int) ([]*Task, error) {
+	reqFunc := newTasksRequestFunc(service.client, projectId, storyId)
+	cursor, err := newCursor(service.client, reqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	if err := cursor.all(&tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListContext is the same as List, but it carries ctx for cancellation and
+// deadlines.
+func (service *TaskService) ListContext(ctx context.Context, projectId, storyId int) ([]*Task, error) {
+	reqFunc := newTasksRequestFunc(service.client, projectId, storyId)
+	ctxReqFunc := func() *http.Request { return reqFunc().WithContext(ctx) }
+	cursor, err := newCursor(service.client, ctxReqFunc, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	if err := cursor.all(&tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// TaskCursor is used to implement the iterator pattern over tasks.
+type TaskCursor struct {
+	*cursor
+	buff []*Task
+}
+
+// Next returns the next task.
+//
+// In case there are no more tasks, io.EOF is returned as an error.
+func (c *TaskCursor) Next() (t *Task, err error) {
+	if len(c.buff) == 0 {
+		_, err = c.next(&c.buff)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.buff) == 0 {
+		err = io.EOF
+	} else {
+		t, c.buff = c.buff[0], c.buff[1:]
+	}
+	return t, err
+}
+
+// Iterate returns a cursor that can be used to iterate over the tasks
+// belonging to the given story. More tasks are fetched on demand as needed.
+func (service *TaskService) Iterate(projectId, storyId int) (*TaskCursor, error) {
+	reqFunc := newTasksRequestFunc(service.client, projectId, storyId)
+	cursor, err := newCursor(service.client, reqFunc, PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskCursor{cursor, make([]*Task, 0)}, nil
+}
+
+// IterateContext is the same as Iterate, but it carries ctx for cancellation
+// and deadlines.
+func (service *TaskService) IterateContext(ctx context.Context, projectId, storyId int) (*TaskCursor, error) {
+	reqFunc := newTasksRequestFunc(service.client, projectId, storyId)
+	ctxReqFunc := func() *http.Request { return reqFunc().WithContext(ctx) }
+	cursor, err := newCursor(service.client, ctxReqFunc, PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskCursor{cursor, make([]*Task, 0)}, nil
+}
+
+// Get returns a single task by ID.
+func (service *TaskService) Get(projectId, storyId, taskId int) (*Task, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", projectId, storyId, taskId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var task Task
+	resp, err := service.client.Do(req, &task)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &task, resp, nil
+}
+
+// GetContext is the same as Get, but it carries ctx for cancellation and
+// deadlines.
+func (service *TaskService) GetContext(ctx context.Context, projectId, storyId, taskId int) (*Task, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", projectId, storyId, taskId)
+	req, err := service.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var task Task
+	resp, err := service.client.Do(req, &task)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &task, resp, nil
+}
+
+// Create adds a new task to the given story.
+func (service *TaskService) Create(projectId, storyId int, task *TaskRequest) (*Task, *http.Response, error) {
+	if task.Description == "" {
+		return nil, nil, &ErrFieldNotSet{"description"}
+	}
+
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks", projectId, storyId)
+	req, err := service.client.NewRequest("POST", u, task)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var newTask Task
+	resp, err := service.client.Do(req, &newTask)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newTask, resp, nil
+}
+
+// CreateContext is the same as Create, but it carries ctx for cancellation
+// and deadlines.
+func (service *TaskService) CreateContext(ctx context.Context, projectId, storyId int, task *TaskRequest) (*Task, *http.Response, error) {
+	if task.Description == "" {
+		return nil, nil, &ErrFieldNotSet{"description"}
+	}
+
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks", projectId, storyId)
+	req, err := service.client.NewRequest("POST", u, task)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var newTask Task
+	resp, err := service.client.Do(req, &newTask)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newTask, resp, nil
+}
+
+// Update updates an existing task.
+func (service *TaskService) Update(projectId, storyId, taskId int, task *TaskRequest) (*Task, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", projectId, storyId, taskId)
+	req, err := service.client.NewRequest("PUT", u, task)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updatedTask Task
+	resp, err := service.client.Do(req, &updatedTask)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedTask, resp, nil
+}
+
+// UpdateContext is the same as Update, but it carries ctx for cancellation
+// and deadlines.
+func (service *TaskService) UpdateContext(ctx context.Context, projectId, storyId, taskId int, task *TaskRequest) (*Task, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", projectId, storyId, taskId)
+	req, err := service.client.NewRequest("PUT", u, task)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var updatedTask Task
+	resp, err := service.client.Do(req, &updatedTask)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &updatedTask, resp, nil
+}
+
+// Delete removes a task from the given story.
+func (service *TaskService) Delete(projectId, storyId, taskId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", projectId, storyId, taskId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.Do(req, nil)
+}
+
+// DeleteContext is the same as Delete, but it carries ctx for cancellation
+// and deadlines.
+func (service *TaskService) DeleteContext(ctx context.Context, projectId, storyId, taskId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", projectId, storyId, taskId)
+	req, err := service.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	return service.client.Do(req, nil)
+}
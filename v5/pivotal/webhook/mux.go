@@ -0,0 +1,240 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"container/list"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// SignatureHeader is the header Mux expects the HMAC-SHA256 signature of
+// the request body in, when a secret is configured via WithSecret.
+const SignatureHeader = "X-Pivotaltracker-Signature"
+
+// maxSeenGUIDs bounds how many activity GUIDs Mux remembers for
+// deduplication. Once the limit is reached, the oldest GUID is evicted to
+// make room, so a long-running Mux does not grow without bound.
+const maxSeenGUIDs = 10000
+
+// Mux is an http.Handler that decodes incoming Pivotal Tracker activity
+// webhooks and dispatches them to the handlers registered via its OnXxx
+// methods. ServeHTTP is safe for concurrent use; the OnXxx methods take no
+// lock and are not safe to call concurrently with each other or with
+// ServeHTTP.
+type Mux struct {
+	secret      []byte
+	maxBodySize int64
+	maxSeen     int
+
+	mu        sync.Mutex
+	seen      map[string]*list.Element
+	seenOrder *list.List
+
+	storyCreate   []func(context.Context, StoryCreateActivity)
+	storyUpdate   []func(context.Context, StoryUpdateActivity)
+	storyDelete   []func(context.Context, StoryDeleteActivity)
+	commentCreate []func(context.Context, CommentCreateActivity)
+	blockerCreate []func(context.Context, BlockerCreateActivity)
+	blockerUpdate []func(context.Context, BlockerUpdateActivity)
+	epicCreate    []func(context.Context, EpicCreateActivity)
+	epicUpdate    []func(context.Context, EpicUpdateActivity)
+}
+
+// MuxOption configures a Mux produced by NewMux.
+type MuxOption func(*Mux)
+
+// WithSecret requires every incoming request to carry a valid
+// SignatureHeader computed over the raw body with secret, rejecting the
+// request with 401 Unauthorized otherwise. Without this option, Mux
+// performs no signature verification.
+func WithSecret(secret []byte) MuxOption {
+	return func(m *Mux) { m.secret = secret }
+}
+
+// WithMaxBodySize overrides the default 1 MiB cap on request body size.
+func WithMaxBodySize(n int64) MuxOption {
+	return func(m *Mux) { m.maxBodySize = n }
+}
+
+// WithMaxSeen overrides the default cap of maxSeenGUIDs on how many
+// activity GUIDs Mux remembers for deduplication.
+func WithMaxSeen(n int) MuxOption {
+	return func(m *Mux) { m.maxSeen = n }
+}
+
+// NewMux returns a Mux ready to have handlers registered on it.
+func NewMux(opts ...MuxOption) *Mux {
+	m := &Mux{
+		maxBodySize: maxBodyBytes,
+		maxSeen:     maxSeenGUIDs,
+		seen:        make(map[string]*list.Element),
+		seenOrder:   list.New(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.maxSeen <= 0 {
+		m.maxSeen = maxSeenGUIDs
+	}
+	return m
+}
+
+// OnStoryCreate registers fn to be called for every story_create_activity.
+func (m *Mux) OnStoryCreate(fn func(ctx context.Context, ev StoryCreateActivity)) {
+	m.storyCreate = append(m.storyCreate, fn)
+}
+
+// OnStoryUpdate registers fn to be called for every story_update_activity.
+func (m *Mux) OnStoryUpdate(fn func(ctx context.Context, ev StoryUpdateActivity)) {
+	m.storyUpdate = append(m.storyUpdate, fn)
+}
+
+// OnStoryDelete registers fn to be called for every story_delete_activity.
+func (m *Mux) OnStoryDelete(fn func(ctx context.Context, ev StoryDeleteActivity)) {
+	m.storyDelete = append(m.storyDelete, fn)
+}
+
+// OnCommentCreate registers fn to be called for every
+// comment_create_activity.
+func (m *Mux) OnCommentCreate(fn func(ctx context.Context, ev CommentCreateActivity)) {
+	m.commentCreate = append(m.commentCreate, fn)
+}
+
+// OnBlockerCreate registers fn to be called for every
+// blocker_create_activity.
+func (m *Mux) OnBlockerCreate(fn func(ctx context.Context, ev BlockerCreateActivity)) {
+	m.blockerCreate = append(m.blockerCreate, fn)
+}
+
+// OnBlockerUpdate registers fn to be called for every
+// blocker_update_activity.
+func (m *Mux) OnBlockerUpdate(fn func(ctx context.Context, ev BlockerUpdateActivity)) {
+	m.blockerUpdate = append(m.blockerUpdate, fn)
+}
+
+// OnEpicCreate registers fn to be called for every epic_create_activity.
+func (m *Mux) OnEpicCreate(fn func(ctx context.Context, ev EpicCreateActivity)) {
+	m.epicCreate = append(m.epicCreate, fn)
+}
+
+// OnEpicUpdate registers fn to be called for every epic_update_activity.
+func (m *Mux) OnEpicUpdate(fn func(ctx context.Context, ev EpicUpdateActivity)) {
+	m.epicUpdate = append(m.epicUpdate, fn)
+}
+
+// ServeHTTP implements http.Handler. It verifies the signature when a
+// secret is configured, decodes the payload, drops activities whose GUID
+// has already been seen, and dispatches the rest to the registered
+// handlers before responding 200 OK.
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, m.maxBodySize+1))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > m.maxBodySize {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if m.secret != nil && !VerifySignature(m.secret, body, r.Header.Get(SignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var activity Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		http.Error(w, "malformed payload", http.StatusBadRequest)
+		return
+	}
+
+	if m.alreadySeen(activity.GUID) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	m.dispatch(r.Context(), newEvent(activity))
+	w.WriteHeader(http.StatusOK)
+}
+
+// alreadySeen reports whether guid has been dispatched before, recording it
+// if not. An empty guid is never deduplicated. The least recently recorded
+// guid is evicted once maxSeen entries are held, bounding memory use for a
+// long-running Mux.
+func (m *Mux) alreadySeen(guid string) bool {
+	if guid == "" {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.seen[guid]; ok {
+		return true
+	}
+
+	m.seen[guid] = m.seenOrder.PushBack(guid)
+	if m.seenOrder.Len() > m.maxSeen {
+		oldest := m.seenOrder.Front()
+		m.seenOrder.Remove(oldest)
+		delete(m.seen, oldest.Value.(string))
+	}
+	return false
+}
+
+func (m *Mux) dispatch(ctx context.Context, ev Event) {
+	switch e := ev.(type) {
+	case StoryCreateActivity:
+		for _, fn := range m.storyCreate {
+			fn(ctx, e)
+		}
+	case StoryUpdateActivity:
+		for _, fn := range m.storyUpdate {
+			fn(ctx, e)
+		}
+	case StoryDeleteActivity:
+		for _, fn := range m.storyDelete {
+			fn(ctx, e)
+		}
+	case CommentCreateActivity:
+		for _, fn := range m.commentCreate {
+			fn(ctx, e)
+		}
+	case BlockerCreateActivity:
+		for _, fn := range m.blockerCreate {
+			fn(ctx, e)
+		}
+	case BlockerUpdateActivity:
+		for _, fn := range m.blockerUpdate {
+			fn(ctx, e)
+		}
+	case EpicCreateActivity:
+		for _, fn := range m.epicCreate {
+			fn(ctx, e)
+		}
+	case EpicUpdateActivity:
+		for _, fn := range m.epicUpdate {
+			fn(ctx, e)
+		}
+	}
+}
+
+// VerifySignature reports whether signature, as sent in SignatureHeader, is
+// the hex-encoded HMAC-SHA256 digest of body computed with secret.
+func VerifySignature(secret, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func hmacHex(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("shh")
+	body := []byte(`{"kind":"story_create_activity"}`)
+
+	valid := hmacHex(secret, body)
+
+	tests := []struct {
+		name      string
+		signature string
+		want      bool
+	}{
+		{name: "valid signature", signature: valid, want: true},
+		{name: "wrong signature", signature: hmacHex(secret, []byte("tampered")), want: false},
+		{name: "empty signature", signature: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(secret, body, tt.signature); got != tt.want {
+				t.Fatalf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMuxAlreadySeen(t *testing.T) {
+	m := NewMux()
+
+	if m.alreadySeen("guid-1") {
+		t.Fatal("first sighting of guid-1 reported as already seen")
+	}
+	if !m.alreadySeen("guid-1") {
+		t.Fatal("second sighting of guid-1 not reported as already seen")
+	}
+	if m.alreadySeen("") {
+		t.Fatal("empty guid should never be deduplicated")
+	}
+	if m.alreadySeen("") {
+		t.Fatal("empty guid should never be deduplicated")
+	}
+}
+
+func TestMuxAlreadySeenEvictsOldestBeyondMaxSeen(t *testing.T) {
+	m := NewMux(WithMaxSeen(2))
+
+	m.alreadySeen("guid-1")
+	m.alreadySeen("guid-2")
+	m.alreadySeen("guid-3")
+
+	if len(m.seen) > 2 {
+		t.Fatalf("len(m.seen) = %d, want at most 2", len(m.seen))
+	}
+	if _, ok := m.seen["guid-1"]; ok {
+		t.Fatal("guid-1 should have been evicted to make room for guid-3")
+	}
+	if _, ok := m.seen["guid-3"]; !ok {
+		t.Fatal("guid-3 should still be remembered")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+// Package webhook decodes the activity payloads Pivotal Tracker posts to
+// a project's configured webhook URL.
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Activity kinds, as reported in Activity.Kind.
+const (
+	KindStoryCreate   = "story_create_activity"
+	KindStoryUpdate   = "story_update_activity"
+	KindStoryDelete   = "story_delete_activity"
+	KindCommentCreate = "comment_create_activity"
+	KindBlockerCreate = "blocker_create_activity"
+	KindBlockerUpdate = "blocker_update_activity"
+	KindEpicCreate    = "epic_create_activity"
+	KindEpicUpdate    = "epic_update_activity"
+)
+
+// maxBodyBytes bounds the size of a webhook payload Parse and Mux will
+// decode. Pivotal Tracker's activity payloads are small JSON documents, so
+// anything larger is rejected rather than decoded.
+const maxBodyBytes = 1 << 20 // 1 MiB
+
+// Change describes a single field-level diff carried by an Activity.
+type Change struct {
+	Kind           string                 `json:"kind"`
+	ChangeType     string                 `json:"change_type"`
+	ID             int                    `json:"id"`
+	OriginalValues map[string]interface{} `json:"original_values,omitempty"`
+	NewValues      map[string]interface{} `json:"new_values,omitempty"`
+}
+
+// Resource identifies a story, epic, comment or other tracker object
+// referenced by an Activity.
+type Resource struct {
+	Kind string `json:"kind"`
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Person identifies the tracker user that performed an Activity.
+type Person struct {
+	Kind     string `json:"kind"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Initials string `json:"initials"`
+	Username string `json:"username,omitempty"`
+}
+
+// Project identifies the project an Activity occurred in.
+type Project struct {
+	Kind string `json:"kind"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Activity is the envelope Pivotal Tracker posts for every webhook event.
+type Activity struct {
+	Kind               string     `json:"kind"`
+	GUID               string     `json:"guid"`
+	ProjectVersion     int        `json:"project_version"`
+	Message            string     `json:"message"`
+	Highlight          string     `json:"highlight,omitempty"`
+	Changes            []Change   `json:"changes"`
+	PrimaryResources   []Resource `json:"primary_resources"`
+	SecondaryResources []Resource `json:"secondary_resources"`
+	Project            Project    `json:"project"`
+	PerformedBy        Person     `json:"performed_by"`
+	OccurredAt         int64      `json:"occurred_at"`
+}
+
+// Event is implemented by every typed activity event returned by Parse and
+// dispatched by Mux. ev.(StoryUpdateActivity) et al. recover the typed
+// payload once Kind is known.
+type Event interface {
+	// ActivityKind returns the raw Pivotal Tracker activity kind, e.g.
+	// "story_update_activity".
+	ActivityKind() string
+
+	// ActivityGUID returns the globally unique ID Pivotal Tracker assigned
+	// to this activity, used to deduplicate redelivered webhooks.
+	ActivityGUID() string
+}
+
+func (a Activity) ActivityKind() string { return a.Kind }
+func (a Activity) ActivityGUID() string { return a.GUID }
+
+// StoryCreateActivity is an Event reported when a story is created.
+type StoryCreateActivity struct{ Activity }
+
+// StoryUpdateActivity is an Event reported when a story is updated.
+type StoryUpdateActivity struct{ Activity }
+
+// StoryDeleteActivity is an Event reported when a story is deleted.
+type StoryDeleteActivity struct{ Activity }
+
+// CommentCreateActivity is an Event reported when a comment is created.
+type CommentCreateActivity struct{ Activity }
+
+// BlockerCreateActivity is an Event reported when a blocker is created.
+type BlockerCreateActivity struct{ Activity }
+
+// BlockerUpdateActivity is an Event reported when a blocker is updated.
+type BlockerUpdateActivity struct{ Activity }
+
+// EpicCreateActivity is an Event reported when an epic is created.
+type EpicCreateActivity struct{ Activity }
+
+// EpicUpdateActivity is an Event reported when an epic is updated.
+type EpicUpdateActivity struct{ Activity }
+
+// newEvent wraps a decoded Activity in the concrete Event type matching its
+// Kind, falling back to the bare Activity for kinds this package does not
+// model explicitly.
+func newEvent(a Activity) Event {
+	switch a.Kind {
+	case KindStoryCreate:
+		return StoryCreateActivity{a}
+	case KindStoryUpdate:
+		return StoryUpdateActivity{a}
+	case KindStoryDelete:
+		return StoryDeleteActivity{a}
+	case KindCommentCreate:
+		return CommentCreateActivity{a}
+	case KindBlockerCreate:
+		return BlockerCreateActivity{a}
+	case KindBlockerUpdate:
+		return BlockerUpdateActivity{a}
+	case KindEpicCreate:
+		return EpicCreateActivity{a}
+	case KindEpicUpdate:
+		return EpicUpdateActivity{a}
+	default:
+		return a
+	}
+}
+
+// Parse decodes a Pivotal Tracker activity webhook payload from r.Body into
+// its typed Event. It does not verify a signature or deduplicate
+// redeliveries; use Mux for that.
+func Parse(r *http.Request) (Event, error) {
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: read payload: %w", err)
+	}
+	if int64(len(body)) > maxBodyBytes {
+		return nil, errors.New("webhook: payload exceeds maximum size")
+	}
+
+	var activity Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		return nil, fmt.Errorf("webhook: decode payload: %w", err)
+	}
+
+	return newEvent(activity), nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2014 Salsita Software
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRejectsOversizedPayload(t *testing.T) {
+	var body bytes.Buffer
+	body.WriteString(`{"kind":"story_create_activity","guid":"abc"}`)
+	body.WriteString(strings.Repeat(" ", maxBodyBytes))
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+
+	if _, err := Parse(req); err == nil {
+		t.Fatal("Parse did not reject a payload exceeding maxBodyBytes")
+	}
+}
+
+func TestParseAcceptsPayloadWithinLimit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"kind":"story_create_activity","guid":"abc"}`))
+
+	ev, err := Parse(req)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if ev.ActivityGUID() != "abc" {
+		t.Fatalf("ActivityGUID() = %q, want %q", ev.ActivityGUID(), "abc")
+	}
+	if _, ok := ev.(StoryCreateActivity); !ok {
+		t.Fatalf("Parse returned %T, want StoryCreateActivity", ev)
+	}
+}